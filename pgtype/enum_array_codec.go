@@ -0,0 +1,231 @@
+package pgtype
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+)
+
+// EnumArrayCodec is a codec for a one-dimensional array of enum-like text values (PostgreSQL's enum[] and text[]
+// are the common cases). Decoding pulls every element through ElementCodec's interning cache instead of the
+// generic array element path, so a text[] column whose labels repeat across millions of rows -- the normal shape
+// of an enum[] -- produces a []string whose elements share backing storage rather than allocating one string per
+// element per row.
+type EnumArrayCodec struct {
+	ElementCodec *EnumCodec
+}
+
+// NewEnumArrayCodec returns an EnumArrayCodec that interns elements through elementCodec.
+func NewEnumArrayCodec(elementCodec *EnumCodec) *EnumArrayCodec {
+	return &EnumArrayCodec{ElementCodec: elementCodec}
+}
+
+func (*EnumArrayCodec) FormatSupported(format int16) bool {
+	return format == TextFormatCode
+}
+
+func (*EnumArrayCodec) PreferredFormat() int16 {
+	return TextFormatCode
+}
+
+func (c *EnumArrayCodec) PlanEncode(ci *ConnInfo, oid uint32, format int16, value interface{}) EncodePlan {
+	switch format {
+	case TextFormatCode:
+		switch value.(type) {
+		case []string:
+			return encodePlanEnumArrayCodecStringSlice{}
+		}
+
+		if v := reflect.ValueOf(value); v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.String {
+			return encodePlanEnumArrayCodecReflectStringSlice{}
+		}
+	}
+
+	return nil
+}
+
+func (c *EnumArrayCodec) PlanScan(ci *ConnInfo, oid uint32, format int16, target interface{}, actualTarget bool) ScanPlan {
+	switch format {
+	case TextFormatCode:
+		switch target.(type) {
+		case *[]string:
+			return &scanPlanTextAnyToEnumArrayStringSlice{codec: c.ElementCodec}
+		}
+
+		if v := reflect.ValueOf(target); v.Kind() == reflect.Ptr && v.Elem().Kind() == reflect.Slice && v.Elem().Type().Elem().Kind() == reflect.String {
+			return &scanPlanTextAnyToEnumArrayReflectStringSlice{codec: c.ElementCodec, elemType: v.Elem().Type().Elem()}
+		}
+	}
+
+	return nil
+}
+
+func (c *EnumArrayCodec) DecodeDatabaseSQLValue(ci *ConnInfo, oid uint32, format int16, src []byte) (driver.Value, error) {
+	return c.DecodeValue(ci, oid, format, src)
+}
+
+func (c *EnumArrayCodec) DecodeValue(ci *ConnInfo, oid uint32, format int16, src []byte) (interface{}, error) {
+	if src == nil {
+		return nil, nil
+	}
+
+	return parseEnumTextArray(src, c.ElementCodec)
+}
+
+// parseEnumTextArray parses the text format of a one-dimensional PostgreSQL array (e.g. `{a,b,"c d"}`) and
+// interns each element through codec, so repeated labels share one allocation both within an array and across
+// the lifetime of codec.
+//
+// As in PostgreSQL's own array text format, an unquoted NULL token denotes a SQL NULL element, distinct from the
+// quoted string "NULL". Since []string has no null representation, a NULL element decodes to "" and, like any
+// other NULL, is never passed to codec -- it is not interned and does not count against a strict codec's
+// registered member set.
+func parseEnumTextArray(src []byte, codec *EnumCodec) ([]string, error) {
+	if len(src) < 2 || src[0] != '{' || src[len(src)-1] != '}' {
+		return nil, fmt.Errorf("invalid array format: %q", src)
+	}
+
+	body := src[1 : len(src)-1]
+	if len(body) == 0 {
+		return []string{}, nil
+	}
+
+	var elements []string
+	var buf bytes.Buffer
+	quoted := false
+	escaped := false
+	wasQuoted := false
+
+	flush := func() error {
+		isQuoted := wasQuoted
+		wasQuoted = false
+
+		if !isQuoted && string(buf.Bytes()) == "NULL" {
+			buf.Reset()
+			elements = append(elements, "")
+			return nil
+		}
+
+		interned, err := codec.lookupAndCacheString(buf.Bytes())
+		buf.Reset()
+		if err != nil {
+			return err
+		}
+		elements = append(elements, interned)
+		return nil
+	}
+
+	for _, b := range body {
+		switch {
+		case escaped:
+			buf.WriteByte(b)
+			escaped = false
+		case b == '\\':
+			escaped = true
+		case b == '"':
+			quoted = !quoted
+			wasQuoted = true
+		case b == ',' && !quoted:
+			if err := flush(); err != nil {
+				return nil, err
+			}
+		default:
+			buf.WriteByte(b)
+		}
+	}
+	if err := flush(); err != nil {
+		return nil, err
+	}
+
+	return elements, nil
+}
+
+// appendEnumTextArray appends the text format of elements, PostgreSQL-array-quoting each one, to buf.
+func appendEnumTextArray(buf []byte, elements []string) []byte {
+	buf = append(buf, '{')
+	for i, e := range elements {
+		if i > 0 {
+			buf = append(buf, ',')
+		}
+		buf = append(buf, '"')
+		for _, r := range []byte(e) {
+			if r == '"' || r == '\\' {
+				buf = append(buf, '\\')
+			}
+			buf = append(buf, r)
+		}
+		buf = append(buf, '"')
+	}
+	return append(buf, '}')
+}
+
+type encodePlanEnumArrayCodecStringSlice struct{}
+
+func (encodePlanEnumArrayCodecStringSlice) Encode(value interface{}, buf []byte) (newBuf []byte, err error) {
+	return appendEnumTextArray(buf, value.([]string)), nil
+}
+
+type encodePlanEnumArrayCodecReflectStringSlice struct{}
+
+func (encodePlanEnumArrayCodecReflectStringSlice) Encode(value interface{}, buf []byte) (newBuf []byte, err error) {
+	v := reflect.ValueOf(value)
+	elements := make([]string, v.Len())
+	for i := range elements {
+		elements[i] = v.Index(i).String()
+	}
+	return appendEnumTextArray(buf, elements), nil
+}
+
+type scanPlanTextAnyToEnumArrayStringSlice struct {
+	codec *EnumCodec
+}
+
+func (plan *scanPlanTextAnyToEnumArrayStringSlice) Scan(ci *ConnInfo, oid uint32, formatCode int16, src []byte, dst interface{}) error {
+	if src == nil {
+		return fmt.Errorf("cannot scan null into %T", dst)
+	}
+
+	elements, err := parseEnumTextArray(src, plan.codec)
+	if err != nil {
+		return err
+	}
+
+	p := dst.(*[]string)
+	*p = elements
+
+	return nil
+}
+
+// scanPlanTextAnyToEnumArrayReflectStringSlice handles targets like *[]Status where Status is a named string
+// type, the array analogue of scanPlanTextAnyToEnumReflectString.
+type scanPlanTextAnyToEnumArrayReflectStringSlice struct {
+	codec    *EnumCodec
+	elemType reflect.Type
+}
+
+func (plan *scanPlanTextAnyToEnumArrayReflectStringSlice) Scan(ci *ConnInfo, oid uint32, formatCode int16, src []byte, dst interface{}) error {
+	if src == nil {
+		return fmt.Errorf("cannot scan null into %T", dst)
+	}
+
+	elements, err := parseEnumTextArray(src, plan.codec)
+	if err != nil {
+		return err
+	}
+
+	for _, e := range elements {
+		if err := plan.codec.checkTypedMember(plan.elemType, e); err != nil {
+			return err
+		}
+	}
+
+	slice := reflect.MakeSlice(reflect.SliceOf(plan.elemType), len(elements), len(elements))
+	for i, e := range elements {
+		slice.Index(i).SetString(e)
+	}
+
+	reflect.ValueOf(dst).Elem().Set(slice)
+
+	return nil
+}
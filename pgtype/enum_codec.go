@@ -3,24 +3,84 @@ package pgtype
 import (
 	"database/sql/driver"
 	"fmt"
+	"reflect"
+	"sync"
 )
 
 // EnumCodec is a codec that caches the strings it decodes. If the same string is read multiple times only one copy is
 // allocated. These strings are only garbage collected when the EnumCodec is garbage collected. EnumCodec can be used
 // for any text type not only enums, but it should only be used when there are a small number of possible values.
+//
+// A zero value EnumCodec lazily caches whatever strings it is asked to decode, which is appropriate when the set of
+// values is not known ahead of time. Use NewEnumCodec when the full set of PostgreSQL enum labels for an OID is
+// known in advance -- it pre-populates the cache and rejects any value outside that set, bounding memory use and
+// making the codec safe to register once and share across the concurrent Query/Scan calls of a connection pool.
 type EnumCodec struct {
-	membersMap map[string]string // map to quickly lookup member and reuse string instead of allocating
+	mux          sync.RWMutex
+	membersMap   map[string]string                    // map to quickly lookup member and reuse string instead of allocating
+	strict       bool                                 // when true, lookupAndCacheString rejects values not already in membersMap
+	typedMembers map[reflect.Type]map[string]struct{} // labels registered per named string type via RegisterMembers
 }
 
-func (EnumCodec) FormatSupported(format int16) bool {
+// RegisterMembers registers labels as the valid members of the named string type T. Once registered, EnumCodec's
+// scan plans for *T reject decoding a label that is not one of labels, even if the EnumCodec itself is not in
+// strict mode. This lets a single EnumCodec be shared by several enum columns that each scan into their own
+// `type Status string`-style Go type, while still catching an unexpected label on any one of them.
+func RegisterMembers[T ~string](c *EnumCodec, labels ...T) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	if c.typedMembers == nil {
+		c.typedMembers = make(map[reflect.Type]map[string]struct{})
+	}
+
+	set := make(map[string]struct{}, len(labels))
+	for _, label := range labels {
+		set[string(label)] = struct{}{}
+	}
+
+	c.typedMembers[reflect.TypeOf(T(""))] = set
+}
+
+// checkTypedMember returns an error if t has members registered via RegisterMembers and label is not one of
+// them. A t with no registered members allows any label.
+func (c *EnumCodec) checkTypedMember(t reflect.Type, label string) error {
+	c.mux.RLock()
+	defer c.mux.RUnlock()
+
+	members, ok := c.typedMembers[t]
+	if !ok {
+		return nil
+	}
+
+	if _, ok := members[label]; !ok {
+		return fmt.Errorf("%q is not a registered member of %s", label, t)
+	}
+
+	return nil
+}
+
+// NewEnumCodec returns an EnumCodec pre-populated with members, the exact set of labels PostgreSQL has registered
+// for the enum OID it will be used with. Decoding a value outside that set returns an error instead of growing the
+// cache, so the memory used by the returned EnumCodec is bounded by len(members).
+func NewEnumCodec(members []string) *EnumCodec {
+	membersMap := make(map[string]string, len(members))
+	for _, m := range members {
+		membersMap[m] = m
+	}
+
+	return &EnumCodec{membersMap: membersMap, strict: true}
+}
+
+func (*EnumCodec) FormatSupported(format int16) bool {
 	return format == TextFormatCode || format == BinaryFormatCode
 }
 
-func (EnumCodec) PreferredFormat() int16 {
+func (*EnumCodec) PreferredFormat() int16 {
 	return TextFormatCode
 }
 
-func (EnumCodec) PlanEncode(ci *ConnInfo, oid uint32, format int16, value interface{}) EncodePlan {
+func (*EnumCodec) PlanEncode(ci *ConnInfo, oid uint32, format int16, value interface{}) EncodePlan {
 	switch format {
 	case TextFormatCode, BinaryFormatCode:
 		switch value.(type) {
@@ -33,6 +93,10 @@ func (EnumCodec) PlanEncode(ci *ConnInfo, oid uint32, format int16, value interf
 		case TextValuer:
 			return encodePlanTextCodecTextValuer{}
 		}
+
+		if v := reflect.ValueOf(value); v.Kind() == reflect.String {
+			return encodePlanTextCodecReflectString{}
+		}
 	}
 
 	return nil
@@ -51,6 +115,10 @@ func (c *EnumCodec) PlanScan(ci *ConnInfo, oid uint32, format int16, target inte
 		case *rune:
 			return scanPlanTextAnyToRune{}
 		}
+
+		if v := reflect.ValueOf(target); v.Kind() == reflect.Ptr && v.Elem().Kind() == reflect.String {
+			return &scanPlanTextAnyToEnumReflectString{codec: c, targetType: v.Elem().Type()}
+		}
 	}
 
 	return nil
@@ -65,21 +133,62 @@ func (c *EnumCodec) DecodeValue(ci *ConnInfo, oid uint32, format int16, src []by
 		return nil, nil
 	}
 
-	return c.lookupAndCacheString(src), nil
+	return c.lookupAndCacheString(src)
 }
 
-// lookupAndCacheString looks for src in the members map. If it is not found it is added to the map.
-func (c *EnumCodec) lookupAndCacheString(src []byte) string {
+// lookupAndCacheString looks for src in the members map. If it is not found and the codec is not strict it is
+// added to the map. If it is not found and the codec is strict, an error is returned. It is safe to call
+// lookupAndCacheString concurrently from multiple goroutines, as is normal for a *ConnInfo shared across a
+// connection pool.
+func (c *EnumCodec) lookupAndCacheString(src []byte) (string, error) {
+	c.mux.RLock()
+	s, found := c.membersMap[string(src)]
+	c.mux.RUnlock()
+	if found {
+		return s, nil
+	}
+
+	if c.strict {
+		return "", fmt.Errorf("unregistered enum member: %q", src)
+	}
+
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
 	if c.membersMap == nil {
 		c.membersMap = make(map[string]string)
 	}
 
 	if s, found := c.membersMap[string(src)]; found {
-		return s
-	} else {
-		c.membersMap[s] = s
-		return s
+		return s, nil
+	}
+
+	key := string(src)
+	c.membersMap[key] = key
+	return key, nil
+}
+
+// Members returns the strings c has observed (or, for an EnumCodec created with NewEnumCodec, pre-registered),
+// in no particular order.
+func (c *EnumCodec) Members() []string {
+	c.mux.RLock()
+	defer c.mux.RUnlock()
+
+	members := make([]string, 0, len(c.membersMap))
+	for m := range c.membersMap {
+		members = append(members, m)
 	}
+
+	return members
+}
+
+// Len returns the number of distinct strings c has observed (or, for an EnumCodec created with NewEnumCodec,
+// pre-registered), the same as len(c.Members()).
+func (c *EnumCodec) Len() int {
+	c.mux.RLock()
+	defer c.mux.RUnlock()
+
+	return len(c.membersMap)
 }
 
 type scanPlanTextAnyToEnumString struct {
@@ -91,12 +200,52 @@ func (plan *scanPlanTextAnyToEnumString) Scan(ci *ConnInfo, oid uint32, formatCo
 		return fmt.Errorf("cannot scan null into %T", dst)
 	}
 
+	s, err := plan.codec.lookupAndCacheString(src)
+	if err != nil {
+		return err
+	}
+
 	p := (dst).(*string)
-	*p = plan.codec.lookupAndCacheString(src)
+	*p = s
 
 	return nil
 }
 
+// scanPlanTextAnyToEnumReflectString handles targets whose underlying kind is string but that are not *string
+// itself, such as a user-defined `type Status string` enum. It writes the interned string through reflection
+// instead of a type assertion.
+type scanPlanTextAnyToEnumReflectString struct {
+	codec      *EnumCodec
+	targetType reflect.Type
+}
+
+func (plan *scanPlanTextAnyToEnumReflectString) Scan(ci *ConnInfo, oid uint32, formatCode int16, src []byte, dst interface{}) error {
+	if src == nil {
+		return fmt.Errorf("cannot scan null into %T", dst)
+	}
+
+	s, err := plan.codec.lookupAndCacheString(src)
+	if err != nil {
+		return err
+	}
+
+	if err := plan.codec.checkTypedMember(plan.targetType, s); err != nil {
+		return err
+	}
+
+	reflect.ValueOf(dst).Elem().SetString(s)
+
+	return nil
+}
+
+// encodePlanTextCodecReflectString handles values whose underlying kind is string but that are not string
+// itself, such as a user-defined `type Status string` enum.
+type encodePlanTextCodecReflectString struct{}
+
+func (encodePlanTextCodecReflectString) Encode(value interface{}, buf []byte) (newBuf []byte, err error) {
+	return append(buf, reflect.ValueOf(value).String()...), nil
+}
+
 type scanPlanTextAnyToEnumTextScanner struct {
 	codec *EnumCodec
 }
@@ -108,5 +257,10 @@ func (plan *scanPlanTextAnyToEnumTextScanner) Scan(ci *ConnInfo, oid uint32, for
 		return scanner.ScanText(Text{})
 	}
 
-	return scanner.ScanText(Text{String: plan.codec.lookupAndCacheString(src), Valid: true})
+	s, err := plan.codec.lookupAndCacheString(src)
+	if err != nil {
+		return err
+	}
+
+	return scanner.ScanText(Text{String: s, Valid: true})
 }
@@ -0,0 +1,181 @@
+package pgtype
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestEnumCodecLookupAndCacheStringInternsRepeatedLabels(t *testing.T) {
+	codec := NewEnumCodec([]string{"active", "inactive"})
+
+	first, err := codec.DecodeValue(nil, 0, TextFormatCode, []byte("active"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	second, err := codec.DecodeValue(nil, 0, TextFormatCode, []byte("active"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if stringDataPtr(first.(string)) != stringDataPtr(second.(string)) {
+		t.Error("decoding the same label twice should return strings backed by the same array")
+	}
+}
+
+func TestEnumCodecLookupAndCacheStringActuallyCaches(t *testing.T) {
+	var codec EnumCodec // zero value: lazy, non-strict caching
+
+	if _, err := codec.DecodeValue(nil, 0, TextFormatCode, []byte("active")); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := codec.Len(), 1; got != want {
+		t.Fatalf("Len() = %d, want %d -- a decoded label should be cached, not dropped", got, want)
+	}
+
+	members := codec.Members()
+	if len(members) != 1 || members[0] != "active" {
+		t.Fatalf("Members() = %v, want [active]", members)
+	}
+}
+
+type testStatus string
+
+const (
+	testStatusActive   testStatus = "active"
+	testStatusInactive testStatus = "inactive"
+)
+
+func TestEnumCodecPlanScanNamedStringType(t *testing.T) {
+	codec := NewEnumCodec([]string{"active", "inactive"})
+
+	plan := codec.PlanScan(nil, 0, TextFormatCode, new(testStatus), true)
+	if plan == nil {
+		t.Fatal("expected a scan plan for *testStatus")
+	}
+
+	var dst testStatus
+	if err := plan.Scan(nil, 0, TextFormatCode, []byte("active"), &dst); err != nil {
+		t.Fatal(err)
+	}
+	if dst != testStatusActive {
+		t.Errorf("got %q, want %q", dst, testStatusActive)
+	}
+}
+
+func TestEnumCodecPlanEncodeNamedStringType(t *testing.T) {
+	var codec EnumCodec
+
+	plan := codec.PlanEncode(nil, 0, TextFormatCode, testStatusActive)
+	if plan == nil {
+		t.Fatal("expected an encode plan for testStatus")
+	}
+
+	buf, err := plan.Encode(testStatusActive, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(buf) != "active" {
+		t.Errorf("got %q, want %q", buf, "active")
+	}
+}
+
+func TestEnumCodecRegisterMembersRejectsUnregisteredLabel(t *testing.T) {
+	var codec EnumCodec // lazy, non-strict: any PostgreSQL label is cacheable
+	RegisterMembers(&codec, testStatusActive, testStatusInactive)
+
+	plan := codec.PlanScan(nil, 0, TextFormatCode, new(testStatus), true)
+	if plan == nil {
+		t.Fatal("expected a scan plan for *testStatus")
+	}
+
+	var dst testStatus
+	if err := plan.Scan(nil, 0, TextFormatCode, []byte("active"), &dst); err != nil {
+		t.Fatalf("unexpected error scanning a registered member: %v", err)
+	}
+	if dst != testStatusActive {
+		t.Errorf("got %q, want %q", dst, testStatusActive)
+	}
+
+	err := plan.Scan(nil, 0, TextFormatCode, []byte("banned"), &dst)
+	if err == nil {
+		t.Fatal("expected an error scanning a label that was never registered via RegisterMembers")
+	}
+
+	// A plain *string target is unaffected by RegisterMembers for testStatus -- it has no typed member set of
+	// its own, so the lazily-cached "banned" decodes without error.
+	stringPlan := codec.PlanScan(nil, 0, TextFormatCode, new(string), true)
+	var s string
+	if err := stringPlan.Scan(nil, 0, TextFormatCode, []byte("banned"), &s); err != nil {
+		t.Fatalf("unexpected error scanning into *string: %v", err)
+	}
+	if s != "banned" {
+		t.Errorf("got %q, want %q", s, "banned")
+	}
+}
+
+// TestEnumCodecLazyConcurrentDecodeValue hammers a single shared zero-value (lazy, non-strict) EnumCodec from
+// many goroutines. Run with -race: an unlocked read/write in lookupAndCacheString's auto-cache path will be
+// reported as a data race, mirroring a *ConnInfo shared across a connection pool's goroutines.
+func TestEnumCodecLazyConcurrentDecodeValue(t *testing.T) {
+	var codec EnumCodec
+	labels := []string{"active", "inactive", "banned", "pending"}
+
+	var wg sync.WaitGroup
+	for g := 0; g < 50; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			label := labels[g%len(labels)]
+			for i := 0; i < 100; i++ {
+				s, err := codec.DecodeValue(nil, 0, TextFormatCode, []byte(label))
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				if s.(string) != label {
+					t.Errorf("got %q, want %q", s, label)
+					return
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	if got, want := codec.Len(), len(labels); got != want {
+		t.Errorf("Len() = %d, want %d", got, want)
+	}
+}
+
+// TestEnumCodecStrictConcurrentDecodeValue hammers a single shared NewEnumCodec (strict, pre-populated)
+// EnumCodec from many goroutines. Run with -race to catch an unlocked read on the pre-populated membersMap.
+func TestEnumCodecStrictConcurrentDecodeValue(t *testing.T) {
+	labels := []string{"active", "inactive", "banned", "pending"}
+	codec := NewEnumCodec(labels)
+
+	var wg sync.WaitGroup
+	for g := 0; g < 50; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			label := labels[g%len(labels)]
+			for i := 0; i < 100; i++ {
+				s, err := codec.DecodeValue(nil, 0, TextFormatCode, []byte(label))
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				if s.(string) != label {
+					t.Errorf("got %q, want %q", s, label)
+					return
+				}
+			}
+
+			if _, err := codec.DecodeValue(nil, 0, TextFormatCode, []byte("unregistered")); err == nil {
+				t.Error("expected an error decoding an unregistered member from a strict codec")
+			}
+		}(g)
+	}
+	wg.Wait()
+}